@@ -0,0 +1,41 @@
+package sqlf
+
+import "strconv"
+
+// BindVar is an enum of the different bind var placeholder syntaxes
+// supported by sqlf. Each database driver expects a different syntax for
+// placeholders in a query (eg "?" vs "$1"), so you specify which one
+// you're using when calling Query.Query.
+type BindVar int
+
+const (
+	// SimpleBindVar is the "?" bind var used by eg MySQL and SQLite.
+	SimpleBindVar BindVar = iota
+
+	// PostgresBindVar is the "$n" bind var used by lib/pq and pgx.
+	PostgresBindVar
+
+	// MSSQLBindVar is the "@pn" bind var used by go-mssqldb.
+	MSSQLBindVar
+
+	// NamedBindVar is the ":pn" named-parameter bind var used by
+	// Query.Named for driver-native named/prepared statement binding.
+	NamedBindVar
+)
+
+// BindVar returns the bind var placeholder for the i'th (0-based) bound
+// parameter.
+func (b BindVar) BindVar(i int) string {
+	switch b {
+	case SimpleBindVar:
+		return "?"
+	case PostgresBindVar:
+		return "$" + strconv.Itoa(i+1)
+	case MSSQLBindVar:
+		return "@p" + strconv.Itoa(i+1)
+	case NamedBindVar:
+		return ":p" + strconv.Itoa(i+1)
+	default:
+		panic("sqlf: unknown BindVar")
+	}
+}
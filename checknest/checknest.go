@@ -0,0 +1,319 @@
+// Package checknest analyzes a sqlf.Query's flattened SQL text for
+// subquery positions that some dialects reject in prepared statements -
+// notably older MySQL wire protocol paths, which refuse subqueries in
+// certain positions - and offers ways to make cross-dialect composition
+// of *sqlf.Query values portable without manually restructuring each
+// query by hand.
+package checknest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// Position identifies the syntactic context a subquery appears in within
+// a flattened query's format text.
+type Position int
+
+const (
+	// PositionTopLevel is a subquery whose '(' opens the query text, with
+	// nothing but whitespace before it. A CTE body (`AS (...)`) is
+	// classified as PositionScalarExpr.
+	PositionTopLevel Position = iota
+	// PositionScalarExpr is a subquery used where a single scalar value
+	// is expected, eg `SELECT (SELECT max(id) FROM t)`.
+	PositionScalarExpr
+	// PositionInList is a subquery used as the right-hand side of an IN.
+	PositionInList
+	// PositionFromClause is a subquery used as a derived table in a FROM
+	// or JOIN clause.
+	PositionFromClause
+)
+
+func (p Position) String() string {
+	switch p {
+	case PositionTopLevel:
+		return "top-level"
+	case PositionScalarExpr:
+		return "scalar-expression"
+	case PositionInList:
+		return "in-list"
+	case PositionFromClause:
+		return "from-clause"
+	default:
+		return "unknown"
+	}
+}
+
+// span is a nested subquery found in a flattened query's format text,
+// bounded by the '(' and ')' that enclose it.
+type span struct {
+	start, end int
+	pos        Position
+}
+
+// scan walks format (as returned by sqlf.Query.Inspect) and returns every
+// embedded subquery it finds, tagged with its syntactic position. It
+// understands SQL string literals ('...', with '' as an escaped quote)
+// and --/* */ comments well enough not to mistake a '(' inside one for a
+// subquery boundary.
+func scan(format string) []span {
+	type opener struct {
+		index int
+		pos   Position
+	}
+
+	var spans []span
+	var stack []opener
+	n := len(format)
+	i := 0
+	for i < n {
+		switch c := format[i]; {
+		case c == '\'':
+			i++
+			for i < n {
+				if format[i] == '\'' {
+					if i+1 < n && format[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+
+		case c == '-' && i+1 < n && format[i+1] == '-':
+			for i < n && format[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < n && format[i+1] == '*':
+			i += 2
+			for i+1 < n && !(format[i] == '*' && format[i+1] == '/') {
+				i++
+			}
+			i += 2
+
+		case c == '(':
+			if isSelectNext(format, i+1) {
+				stack = append(stack, opener{index: i, pos: classify(format, i)})
+			} else {
+				stack = append(stack, opener{index: -1})
+			}
+			i++
+
+		case c == ')':
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if top.index >= 0 {
+					spans = append(spans, span{start: top.index, end: i + 1, pos: top.pos})
+				}
+			}
+			i++
+
+		default:
+			i++
+		}
+	}
+	return spans
+}
+
+// isSelectNext reports whether, skipping leading whitespace, format[i:]
+// begins with the keyword SELECT or WITH (a CTE-opening subquery).
+func isSelectNext(format string, i int) bool {
+	for i < len(format) && isSpace(format[i]) {
+		i++
+	}
+	return hasWordFold(format, i, "SELECT") || hasWordFold(format, i, "WITH")
+}
+
+func hasWordFold(s string, i int, word string) bool {
+	if i+len(word) > len(s) || !strings.EqualFold(s[i:i+len(word)], word) {
+		return false
+	}
+	end := i + len(word)
+	return end == len(s) || !isIdentByte(s[end])
+}
+
+// classify determines the syntactic position of a subquery opening at
+// format[parenIdx], by looking at the keyword immediately preceding it.
+func classify(format string, parenIdx int) Position {
+	j := parenIdx
+	for j > 0 && isSpace(format[j-1]) {
+		j--
+	}
+	if j == 0 {
+		return PositionTopLevel
+	}
+
+	wordEnd := j
+	wordStart := j
+	for wordStart > 0 && isIdentByte(format[wordStart-1]) {
+		wordStart--
+	}
+	switch strings.ToUpper(format[wordStart:wordEnd]) {
+	case "IN":
+		return PositionInList
+	case "FROM", "JOIN":
+		return PositionFromClause
+	default:
+		return PositionScalarExpr
+	}
+}
+
+// hasPlaceholder reports whether format contains a bound-arg verb (eg
+// %s, %[1]d). A dialect's restriction on nested subqueries is really a
+// restriction on binding parameters at that position, so a span with no
+// placeholders of its own - eg a subquery promoted to a CTE and
+// re-referenced by name - is never unsafe regardless of position. "%%"
+// is a literal percent, not a placeholder, and is skipped as a pair.
+func hasPlaceholder(format string) bool {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func isSpace(b byte) bool { return b == ' ' || b == '\t' || b == '\n' || b == '\r' }
+
+func isIdentByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// disallowed reports the Positions d rejects for a nested subquery in a
+// single prepared statement. Most dialects don't restrict this at all;
+// only MySQL's older wire protocol paths are this strict.
+func disallowed(d sqlf.Dialect) map[Position]bool {
+	if d == sqlf.MySQL {
+		return map[Position]bool{PositionInList: true, PositionFromClause: true}
+	}
+	return nil
+}
+
+// NestError reports the subquery positions Validate found illegal for a
+// dialect.
+type NestError struct {
+	Positions []Position
+}
+
+func (e *NestError) Error() string {
+	words := make([]string, len(e.Positions))
+	for i, p := range e.Positions {
+		words[i] = p.String()
+	}
+	return fmt.Sprintf("checknest: query has subqueries in positions not supported by this dialect: %s", strings.Join(words, ", "))
+}
+
+// Validate reports whether every nested subquery in q is legal for d. It
+// returns a *NestError identifying the offending positions, or nil if q
+// is safe to send to d as a single prepared statement.
+func Validate(q *sqlf.Query, d sqlf.Dialect) error {
+	bad := disallowed(d)
+	if len(bad) == 0 {
+		return nil
+	}
+
+	format, _, _ := q.Inspect()
+	var positions []Position
+	for _, s := range scan(format) {
+		if bad[s.pos] && hasPlaceholder(format[s.start:s.end]) {
+			positions = append(positions, s.pos)
+		}
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+	return &NestError{Positions: positions}
+}
+
+// firstOffending returns the first span in format that's in a position d
+// disallows and still carries a placeholder, or nil if none remain. scan
+// closes subqueries inside-out, so the innermost offending span - if any
+// - is always found before the spans containing it.
+func firstOffending(format string, bad map[Position]bool) *span {
+	for _, s := range scan(format) {
+		if bad[s.pos] && hasPlaceholder(format[s.start:s.end]) {
+			s := s
+			return &s
+		}
+	}
+	return nil
+}
+
+// Rewrite returns a Query equivalent to q that is safe to send to d as a
+// single prepared statement. Every subquery in a position d rejects that
+// still carries a placeholder of its own is promoted to a CTE
+// (`WITH __sub1 AS (...)`) and its reference site is rewritten to select
+// from that CTE; everything else is left untouched. The result's args
+// are listed in its new placeholder order. If q is already safe for d,
+// Rewrite returns q unchanged.
+//
+// Offending subqueries are promoted innermost-first: once a nested
+// offending subquery is replaced by a bare CTE reference, the span that
+// used to contain it no longer has a placeholder of its own and is
+// re-evaluated on the next pass - so a FROM-clause derived table built
+// around an IN-list subquery (both disallowed positions for MySQL) is
+// fully resolved rather than leaving the inner one promoted in place
+// and still illegal.
+func Rewrite(q *sqlf.Query, d sqlf.Dialect) *sqlf.Query {
+	bad := disallowed(d)
+	if len(bad) == 0 {
+		return q
+	}
+
+	format, args, argIndices := q.Inspect()
+	if firstOffending(format, bad) == nil {
+		return q
+	}
+
+	// order holds the args index bound by each placeholder left in
+	// rewritten; front holds those moved into ctes, in CTE order.
+	order := append([]int(nil), argIndices...)
+	for i := 0; argIndices == nil && i < len(args); i++ {
+		order = append(order, i)
+	}
+	var front []int
+
+	rewritten := format
+	var ctes []string
+	for {
+		s := firstOffending(rewritten, bad)
+		if s == nil {
+			break
+		}
+
+		name := fmt.Sprintf("__sub%d", len(ctes)+1)
+		inner := rewritten[s.start+1 : s.end-1] // drop the wrapping parens
+		ctes = append(ctes, name+" AS ("+inner+")")
+		before := strings.Count(strings.ReplaceAll(rewritten[:s.start], "%%", ""), "%")
+		within := strings.Count(strings.ReplaceAll(inner, "%%", ""), "%")
+		front = append(front, order[before:before+within]...)
+		order = append(order[:before:before], order[before+within:]...)
+
+		var ref string
+		if s.pos == PositionFromClause {
+			ref = name
+		} else {
+			ref = "(SELECT * FROM " + name + ")"
+		}
+		rewritten = rewritten[:s.start] + ref + rewritten[s.end:]
+	}
+
+	newFormat := "WITH " + strings.Join(ctes, ", ") + " " + rewritten
+	newArgs := make([]interface{}, 0, len(front)+len(order))
+	for _, i := range append(front, order...) {
+		newArgs = append(newArgs, args[i])
+	}
+	return sqlf.New(newFormat, newArgs, nil)
+}
@@ -0,0 +1,100 @@
+package checknest_test
+
+import (
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/keegancsmith/sqlf/checknest"
+)
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		Query   *sqlf.Query
+		Dialect sqlf.Dialect
+		WantErr bool
+	}{
+		"postgres_allows_in_list_subquery": {
+			sqlf.Sprintf("SELECT * FROM orders WHERE id IN (%s)", sqlf.Sprintf("SELECT id FROM t WHERE x = %d", 1)),
+			sqlf.Postgres,
+			false,
+		},
+		"mysql_rejects_in_list_subquery": {
+			sqlf.Sprintf("SELECT * FROM orders WHERE id IN (%s)", sqlf.Sprintf("SELECT id FROM t WHERE x = %d", 1)),
+			sqlf.MySQL,
+			true,
+		},
+		"mysql_rejects_from_clause_subquery": {
+			sqlf.Sprintf("SELECT * FROM (%s) d", sqlf.Sprintf("SELECT id FROM t WHERE x = %d", 1)),
+			sqlf.MySQL,
+			true,
+		},
+		"mysql_allows_scalar_subquery": {
+			sqlf.Sprintf("SELECT a = (%s)", sqlf.Sprintf("SELECT max(id) FROM t")),
+			sqlf.MySQL,
+			false,
+		},
+		"no_nested_query_is_always_safe": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", "foo"),
+			sqlf.MySQL,
+			false,
+		},
+	}
+
+	for tn, tc := range cases {
+		err := checknest.Validate(tc.Query, tc.Dialect)
+		if tc.WantErr && err == nil {
+			t.Errorf("%s: expected an error, got nil", tn)
+		}
+		if !tc.WantErr && err != nil {
+			t.Errorf("%s: expected no error, got: %v", tn, err)
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	q := sqlf.Sprintf("SELECT * FROM orders WHERE id IN (%s)", sqlf.Sprintf("SELECT id FROM t WHERE x = %d", 1))
+
+	rewritten := checknest.Rewrite(q, sqlf.MySQL)
+	if err := checknest.Validate(rewritten, sqlf.MySQL); err != nil {
+		t.Fatalf("rewritten query still invalid: %v", err)
+	}
+
+	want := "WITH __sub1 AS (SELECT id FROM t WHERE x = %s) SELECT * FROM orders WHERE id IN (SELECT * FROM __sub1)"
+	format, args, _ := rewritten.Inspect()
+	if format != want {
+		t.Errorf("expected format: %q, got: %q", want, format)
+	}
+	if len(args) != 1 || args[0] != 1 {
+		t.Errorf("expected args [1], got: %v", args)
+	}
+
+	// A query already safe for the dialect is returned unchanged.
+	safe := sqlf.Sprintf("SELECT a = (%s)", sqlf.Sprintf("SELECT max(id) FROM t"))
+	if got := checknest.Rewrite(safe, sqlf.MySQL); got != safe {
+		t.Errorf("expected Rewrite to return the same *Query when already safe")
+	}
+}
+
+func TestRewriteNestedOffendingSpans(t *testing.T) {
+	// A MySQL-disallowed FROM-clause derived table that itself contains
+	// a MySQL-disallowed IN-list subquery: the inner offending span is
+	// contained within the outer one. Rewrite must resolve both - not
+	// just avoid the panic their overlapping ranges used to cause - by
+	// promoting the inner one first, which then leaves the FROM-clause
+	// span with no placeholder of its own and so no longer offending.
+	q := sqlf.Sprintf("SELECT * FROM (%s) d", sqlf.Sprintf("SELECT id FROM t2 WHERE y IN (%s)", sqlf.Sprintf("SELECT z FROM t3 WHERE w = %d", 5)))
+
+	rewritten := checknest.Rewrite(q, sqlf.MySQL)
+	if err := checknest.Validate(rewritten, sqlf.MySQL); err != nil {
+		t.Fatalf("rewritten query still invalid: %v", err)
+	}
+
+	want := "WITH __sub1 AS (SELECT z FROM t3 WHERE w = %s) SELECT * FROM (SELECT id FROM t2 WHERE y IN (SELECT * FROM __sub1)) d"
+	format, args, _ := rewritten.Inspect()
+	if format != want {
+		t.Errorf("expected format: %q, got: %q", want, format)
+	}
+	if len(args) != 1 || args[0] != 5 {
+		t.Errorf("expected args [5], got: %v", args)
+	}
+}
@@ -0,0 +1,217 @@
+package sqlf
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect is a per-database extension point consulted by Query.QueryDialect
+// when producing SQL. It generalizes BindVar with the ability to render an
+// argument as a type-annotated SQL literal instead of a bound parameter.
+type Dialect interface {
+	// BindVar returns the placeholder syntax for the i'th (0-based) bound
+	// parameter, eg "$1", "?", "@p1".
+	BindVar(i int) string
+
+	// Sqlify is given the value wrapped by Literal and decides whether to
+	// render it as a literal embedded in the SQL text. If keepAsBind is
+	// true, literal is ignored and the argument is bound as usual via
+	// BindVar - dialects return this for any type they don't have a
+	// literal formatter for. Otherwise literal is the (already escaped)
+	// SQL text to splice in verbatim and the argument is dropped from the
+	// bound args.
+	Sqlify(arg interface{}) (literal string, keepAsBind bool)
+}
+
+// literalArg marks an argument as a candidate for inline literal
+// rendering by a Dialect.
+type literalArg struct{ v interface{} }
+
+// Literal marks arg as a candidate for inline rendering as a
+// type-annotated SQL literal when the query is produced via
+// Query.QueryDialect. Dialects that don't have a literal formatter for
+// the underlying type fall back to binding it as a normal parameter.
+// Outside of QueryDialect (plain Query/Args), Literal is a no-op and arg
+// is bound normally.
+func Literal(arg interface{}) interface{} {
+	return literalArg{arg}
+}
+
+// unwrapLiteral returns the value wrapped by Literal, and whether arg was
+// wrapped at all.
+func unwrapLiteral(arg interface{}) (interface{}, bool) {
+	if la, ok := arg.(literalArg); ok {
+		return la.v, true
+	}
+	return arg, false
+}
+
+// QueryDialect renders the query text and bound args using d's BindVar
+// placeholder syntax, additionally consulting d.Sqlify for any argument
+// wrapped with Literal. Args the dialect chooses to render as a literal
+// are spliced directly into the returned SQL text and dropped from the
+// returned args slice; every other arg remains a bound parameter numbered
+// via d.BindVar.
+func (q *Query) QueryDialect(d Dialect) (string, []interface{}) {
+	indices := q.argIndices
+	if indices == nil {
+		indices = make([]int, len(q.args))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+
+	literals := make(map[int]string, len(q.args))
+	for i, arg := range q.args {
+		v, wrapped := unwrapLiteral(arg)
+		if !wrapped {
+			continue
+		}
+		if lit, keepAsBind := d.Sqlify(v); !keepAsBind {
+			literals[i] = lit
+		}
+	}
+
+	var resultArgs []interface{}
+	bound := make(map[int]int, len(q.args))
+	ph := make([]interface{}, len(indices))
+	for i, argIdx := range indices {
+		if lit, ok := literals[argIdx]; ok {
+			ph[i] = ignoreFormat{lit}
+			continue
+		}
+		pos, ok := bound[argIdx]
+		if !ok {
+			pos = len(resultArgs)
+			bound[argIdx] = pos
+			v, _ := unwrapLiteral(q.args[argIdx])
+			resultArgs = append(resultArgs, v)
+		}
+		ph[i] = ignoreFormat{d.BindVar(pos)}
+	}
+
+	return fmt.Sprintf(q.fmt, ph...), resultArgs
+}
+
+// postgresFloat8Literal renders f as a Postgres float8 literal. NaN and
+// the infinities aren't valid unquoted numeric tokens in Postgres - they
+// have to be written as quoted strings ('NaN', 'Infinity', '-Infinity')
+// for the ::float8 cast to parse them.
+func postgresFloat8Literal(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "'NaN'"
+	case math.IsInf(f, 1):
+		return "'Infinity'"
+	case math.IsInf(f, -1):
+		return "'-Infinity'"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) BindVar(i int) string { return PostgresBindVar.BindVar(i) }
+
+func (postgresDialect) Sqlify(arg interface{}) (string, bool) {
+	switch v := arg.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10) + "::int8", false
+	case int:
+		return strconv.Itoa(v) + "::int8", false
+	case float64:
+		return postgresFloat8Literal(v) + "::float8", false
+	case []byte:
+		return `'\x` + hex.EncodeToString(v) + `'::bytea`, false
+	case time.Time:
+		return "'" + v.UTC().Format(time.RFC3339Nano) + "'::timestamptz", false
+	default:
+		return "", true
+	}
+}
+
+// Postgres is the built-in Dialect for PostgreSQL. It uses "$n" bind
+// vars and, for arguments wrapped with Literal, emits explicitly
+// type-cast literals (eg 123::int8) so the server doesn't need to infer
+// parameter types.
+var Postgres Dialect = postgresDialect{}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) BindVar(i int) string { return SimpleBindVar.BindVar(i) }
+
+func (mysqlDialect) Sqlify(arg interface{}) (string, bool) {
+	switch v := arg.(type) {
+	case int64:
+		return "CAST(" + strconv.FormatInt(v, 10) + " AS SIGNED)", false
+	case int:
+		return "CAST(" + strconv.Itoa(v) + " AS SIGNED)", false
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'", false
+	default:
+		return "", true
+	}
+}
+
+// MySQL is the built-in Dialect for MySQL/MariaDB. It uses "?" bind vars
+// and, for arguments wrapped with Literal, emits CAST(... AS SIGNED) for
+// integers and hex string literals for []byte.
+var MySQL Dialect = mysqlDialect{}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) BindVar(i int) string { return SimpleBindVar.BindVar(i) }
+
+func (sqliteDialect) Sqlify(arg interface{}) (string, bool) {
+	switch v := arg.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10), false
+	case int:
+		return strconv.Itoa(v), false
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), false
+	case bool:
+		if v {
+			return "1", false
+		}
+		return "0", false
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", false
+	default:
+		return "", true
+	}
+}
+
+// SQLite is the built-in Dialect for SQLite. It uses "?" bind vars.
+// SQLite has no type-suffixed literal syntax, so arguments wrapped with
+// Literal are rendered as plain (escaped) literals rather than cast
+// expressions.
+var SQLite Dialect = sqliteDialect{}
+
+type mssqlDialect struct{}
+
+func (mssqlDialect) BindVar(i int) string { return MSSQLBindVar.BindVar(i) }
+
+func (mssqlDialect) Sqlify(arg interface{}) (string, bool) {
+	switch v := arg.(type) {
+	case int64:
+		return "CAST(" + strconv.FormatInt(v, 10) + " AS BIGINT)", false
+	case int:
+		return "CAST(" + strconv.Itoa(v) + " AS BIGINT)", false
+	case []byte:
+		return "0x" + hex.EncodeToString(v), false
+	default:
+		return "", true
+	}
+}
+
+// MSSQL is the built-in Dialect for Microsoft SQL Server. It uses
+// "@pn" bind vars and, for arguments wrapped with Literal, emits
+// CAST(... AS BIGINT) for integers and 0x-prefixed hex literals for
+// []byte.
+var MSSQL Dialect = mssqlDialect{}
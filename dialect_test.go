@@ -0,0 +1,97 @@
+package sqlf_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+func TestQueryDialect(t *testing.T) {
+	cases := map[string]struct {
+		Query    *sqlf.Query
+		Dialect  sqlf.Dialect
+		Want     string
+		WantArgs []interface{}
+	}{
+		"no_literals_falls_back_to_bindvar": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s AND b = %d", "foo", 1),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = $1 AND b = $2",
+			[]interface{}{"foo", 1},
+		},
+		"postgres_literal_int": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s AND b = %s", sqlf.Literal(int64(123)), "foo"),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = 123::int8 AND b = $1",
+			[]interface{}{"foo"},
+		},
+		"postgres_literal_unknown_type_falls_back": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(true)),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = $1",
+			[]interface{}{true},
+		},
+		"mysql_literal_int": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(int64(7))),
+			sqlf.MySQL,
+			"SELECT * FROM t WHERE a = CAST(7 AS SIGNED)",
+			nil,
+		},
+		"sqlite_literal_string": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal("o'brien")),
+			sqlf.SQLite,
+			"SELECT * FROM t WHERE a = 'o''brien'",
+			nil,
+		},
+		"mssql_bindvar_style": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s AND b = %s", "foo", "bar"),
+			sqlf.MSSQL,
+			"SELECT * FROM t WHERE a = @p1 AND b = @p2",
+			[]interface{}{"foo", "bar"},
+		},
+		"literal_reused_via_explicit_index": {
+			sqlf.Sprintf("a = %[1]s OR a = %[1]s", sqlf.Literal(int64(1))),
+			sqlf.Postgres,
+			"a = 1::int8 OR a = 1::int8",
+			nil,
+		},
+		"postgres_literal_float_nan": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(math.NaN())),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = 'NaN'::float8",
+			nil,
+		},
+		"postgres_literal_float_positive_infinity": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(math.Inf(1))),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = 'Infinity'::float8",
+			nil,
+		},
+		"postgres_literal_float_negative_infinity": {
+			sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(math.Inf(-1))),
+			sqlf.Postgres,
+			"SELECT * FROM t WHERE a = '-Infinity'::float8",
+			nil,
+		},
+	}
+
+	for tn, tc := range cases {
+		gotSQL, gotArgs := tc.Query.QueryDialect(tc.Dialect)
+		if gotSQL != tc.Want {
+			t.Errorf("%s: expected query: %q, got: %q", tn, tc.Want, gotSQL)
+		}
+		if !reflect.DeepEqual(gotArgs, tc.WantArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", tn, tc.WantArgs, gotArgs)
+		}
+	}
+}
+
+func TestArgsUnwrapsLiteral(t *testing.T) {
+	q := sqlf.Sprintf("SELECT * FROM t WHERE a = %s", sqlf.Literal(int64(42)))
+	want := []interface{}{int64(42)}
+	if got := q.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected args: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,46 @@
+package dml
+
+import (
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// DeleteBuilder builds a DELETE statement.
+type DeleteBuilder struct {
+	table     string
+	where     *sqlf.Query
+	returning []string
+}
+
+// Delete starts building a "DELETE FROM table" statement.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Where sets the WHERE clause. cond is typically built with
+// sqlf.Sprintf or sqlf.Join.
+func (b *DeleteBuilder) Where(cond *sqlf.Query) *DeleteBuilder {
+	b.where = cond
+	return b
+}
+
+// Returning sets the RETURNING clause columns.
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	b.returning = columns
+	return b
+}
+
+// Query builds the *sqlf.Query for the statement.
+func (b *DeleteBuilder) Query() *sqlf.Query {
+	sql := "DELETE FROM " + b.table
+	var args []interface{}
+	if b.where != nil {
+		sql += " WHERE %s"
+		args = append(args, b.where)
+	}
+	if len(b.returning) > 0 {
+		sql += " RETURNING " + strings.Join(b.returning, ", ")
+	}
+	return sqlf.Sprintf(sql, args...)
+}
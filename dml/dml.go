@@ -0,0 +1,28 @@
+// Package dml provides fluent builders for the common INSERT, UPDATE,
+// DELETE and upsert statement shapes, each of which ultimately lowers to
+// a *sqlf.Query via sqlf.Sprintf and sqlf.Join, so nested *sqlf.Query
+// values (subselects, computed defaults, JSON operators, ...) remain
+// first-class arguments anywhere a value is expected.
+package dml
+
+// Assignment is a single "column = value" pair used by
+// InsertBuilder.DoUpdate and UpdateBuilder.Set.
+type Assignment struct {
+	Column string
+	Value  interface{}
+}
+
+// Set returns an Assignment pairing column with value, for use with
+// InsertBuilder.DoUpdate.
+func Set(column string, value interface{}) Assignment {
+	return Assignment{Column: column, Value: value}
+}
+
+func indexOfColumn(columns []string, name string) int {
+	for i, c := range columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
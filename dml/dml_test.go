@@ -0,0 +1,212 @@
+package dml_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/keegancsmith/sqlf/dml"
+)
+
+func TestInsert(t *testing.T) {
+	cases := map[string]struct {
+		Query    *sqlf.Query
+		Want     string
+		WantArgs []interface{}
+	}{
+		"single_row": {
+			dml.Insert("users").Columns("id", "name").Values(1, "alice").Query(),
+			"INSERT INTO users (id, name) VALUES ($1, $2)",
+			[]interface{}{1, "alice"},
+		},
+		"multi_row": {
+			dml.Insert("users").Columns("id", "name").Values(1, "alice").Values(2, "bob").Query(),
+			"INSERT INTO users (id, name) VALUES ($1, $2) , ($3, $4)",
+			[]interface{}{1, "alice", 2, "bob"},
+		},
+		"on_conflict_do_nothing": {
+			dml.Insert("users").Columns("id", "name").Values(1, "alice").OnConflict("id").Query(),
+			"INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING",
+			[]interface{}{1, "alice"},
+		},
+		"returning": {
+			dml.Insert("users").Columns("id", "name").Values(1, "alice").Returning("id").Query(),
+			"INSERT INTO users (id, name) VALUES ($1, $2) RETURNING id",
+			[]interface{}{1, "alice"},
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := tc.Query.Query(sqlf.PostgresBindVar); got != tc.Want {
+			t.Errorf("%s: expected query: %q, got: %q", tn, tc.Want, got)
+		}
+		if got := tc.Query.Args(); !reflect.DeepEqual(got, tc.WantArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", tn, tc.WantArgs, got)
+		}
+	}
+}
+
+func TestInsertUpsertReusesSharedArg(t *testing.T) {
+	q := dml.Insert("users").
+		Columns("id", "name").
+		Values(1, "alice").
+		OnConflict("id").
+		DoUpdate(dml.Set("name", "alice")).
+		Query()
+
+	want := "INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = $2"
+	if got := q.Query(sqlf.PostgresBindVar); got != want {
+		t.Errorf("expected query: %q, got: %q", want, got)
+	}
+	wantArgs := []interface{}{1, "alice"}
+	if got := q.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("expected args: %v, got: %v (shared arg should be bound once)", wantArgs, got)
+	}
+}
+
+func TestInsertUpsertDistinctValueNotShared(t *testing.T) {
+	q := dml.Insert("counters").
+		Columns("id", "count").
+		Values(1, 1).
+		OnConflict("id").
+		DoUpdate(dml.Set("count", 2)). // distinct from the inserted value of 1
+		Query()
+
+	want := "INSERT INTO counters (id, count) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET count = $3"
+	if got := q.Query(sqlf.PostgresBindVar); got != want {
+		t.Errorf("expected query: %q, got: %q", want, got)
+	}
+	wantArgs := []interface{}{1, 1, 2}
+	if got := q.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("expected args: %v, got: %v", wantArgs, got)
+	}
+}
+
+func TestInsertUpsertRequiresExactlyOneRow(t *testing.T) {
+	cases := map[string]*dml.InsertBuilder{
+		"multi_row": dml.Insert("users").
+			Columns("id", "name").
+			Values(1, "alice").
+			Values(2, "bob").
+			OnConflict("id").
+			DoUpdate(dml.Set("name", "alice")),
+		"no_row": dml.Insert("users").
+			Columns("id", "name").
+			OnConflict("id").
+			DoUpdate(dml.Set("name", "alice")),
+	}
+
+	for tn, b := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Query to panic", tn)
+				}
+			}()
+			b.Query()
+		}()
+	}
+}
+
+func TestInsertUpsertRequiresOnConflictAndDoUpdate(t *testing.T) {
+	cases := map[string]*dml.InsertBuilder{
+		"no_on_conflict": dml.Insert("users").
+			Columns("id", "name").
+			Values(1, "alice").
+			DoUpdate(dml.Set("name", "alice")),
+		"no_do_update": dml.Insert("users").
+			Columns("id", "name").
+			Values(1, "alice").
+			OnConflict("id"),
+	}
+
+	for tn, b := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Upsert to panic", tn)
+				}
+			}()
+			dml.Upsert(b, sqlf.Postgres)
+		}()
+	}
+}
+
+func TestInsertUpsertRequiresMatchingArity(t *testing.T) {
+	cases := map[string]func() *sqlf.Query{
+		"plain": func() *sqlf.Query {
+			return dml.Insert("users").Columns("id", "name").Values(1).Query()
+		},
+		"upsert": func() *sqlf.Query {
+			return dml.Insert("users").
+				Columns("id", "name").
+				Values(1).
+				OnConflict("id").
+				DoUpdate(dml.Set("name", "alice")).
+				Query()
+		},
+	}
+
+	for tn, build := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Query to panic", tn)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestUpsertMySQLStyle(t *testing.T) {
+	b := dml.Insert("users").
+		Columns("id", "name").
+		Values(1, "alice").
+		OnConflict("id").
+		DoUpdate(dml.Set("name", "alice"))
+
+	q := dml.Upsert(b, sqlf.MySQL)
+	want := "INSERT INTO users (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = ?"
+	if got := q.Query(sqlf.SimpleBindVar); got != want {
+		t.Errorf("expected query: %q, got: %q", want, got)
+	}
+	wantArgs := []interface{}{1, "alice"}
+	if got := q.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("expected args: %v, got: %v", wantArgs, got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	q := dml.Update("users").
+		Set("name", "alice").
+		Set("age", 30).
+		Where(sqlf.Sprintf("id = %d", 1)).
+		Returning("id").
+		Query()
+
+	want := "UPDATE users SET name = $1 , age = $2 WHERE id = $3 RETURNING id"
+	if got := q.Query(sqlf.PostgresBindVar); got != want {
+		t.Errorf("expected query: %q, got: %q", want, got)
+	}
+	wantArgs := []interface{}{"alice", 30, 1}
+	if got := q.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("expected args: %v, got: %v", wantArgs, got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	q := dml.Delete("users").
+		Where(sqlf.Sprintf("id = %d", 1)).
+		Returning("id").
+		Query()
+
+	want := "DELETE FROM users WHERE id = $1 RETURNING id"
+	if got := q.Query(sqlf.PostgresBindVar); got != want {
+		t.Errorf("expected query: %q, got: %q", want, got)
+	}
+	wantArgs := []interface{}{1}
+	if got := q.Args(); !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("expected args: %v, got: %v", wantArgs, got)
+	}
+}
@@ -0,0 +1,172 @@
+package dml
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// InsertBuilder builds an INSERT statement, optionally with an upsert
+// (ON CONFLICT / DO UPDATE) clause and a RETURNING clause.
+type InsertBuilder struct {
+	table      string
+	columns    []string
+	rows       [][]interface{}
+	onConflict []string
+	doUpdate   []Assignment
+	returning  []string
+}
+
+// Insert starts building an "INSERT INTO table" statement.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Columns sets the columns being inserted into.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values appends a row of values to insert, in the order given to
+// Columns. Calling Values more than once inserts multiple rows.
+func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// OnConflict sets the conflict target columns for an upsert.
+func (b *InsertBuilder) OnConflict(columns ...string) *InsertBuilder {
+	b.onConflict = columns
+	return b
+}
+
+// DoUpdate sets the assignments to apply on conflict, turning the
+// INSERT into an upsert; it requires exactly one row (from a single
+// call to Values) - Query panics otherwise. A value that
+// reflect.DeepEqual-matches the value given to Values for the same
+// column - a common upsert pattern - is bound once and reused, rather
+// than being sent to the driver twice.
+func (b *InsertBuilder) DoUpdate(assignments ...Assignment) *InsertBuilder {
+	b.doUpdate = assignments
+	return b
+}
+
+// Returning sets the RETURNING clause columns.
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	b.returning = columns
+	return b
+}
+
+// Query builds the *sqlf.Query for the statement, using the
+// Postgres/SQLite `ON CONFLICT (...) DO UPDATE SET ...` upsert syntax if
+// DoUpdate was called. For MySQL's `ON DUPLICATE KEY UPDATE` syntax
+// instead, use Upsert.
+func (b *InsertBuilder) Query() *sqlf.Query {
+	if len(b.doUpdate) > 0 {
+		return b.upsertQuery(standardUpsert)
+	}
+	return b.plainQuery()
+}
+
+func (b *InsertBuilder) plainQuery() *sqlf.Query {
+	rowQueries := make([]*sqlf.Query, len(b.rows))
+	for i, row := range b.rows {
+		if len(row) != len(b.columns) {
+			panic(fmt.Sprintf("dml: Values call %d has %d value(s), want %d to match Columns", i, len(row), len(b.columns)))
+		}
+		placeholders := make([]string, len(row))
+		for j := range placeholders {
+			placeholders[j] = "%s"
+		}
+		rowQueries[i] = sqlf.Sprintf("("+strings.Join(placeholders, ", ")+")", row...)
+	}
+	valuesClause := sqlf.Join(rowQueries, ",")
+
+	sql := "INSERT INTO " + b.table + " (" + strings.Join(b.columns, ", ") + ") VALUES %s"
+	if len(b.onConflict) > 0 {
+		sql += " ON CONFLICT (" + strings.Join(b.onConflict, ", ") + ") DO NOTHING"
+	}
+	if len(b.returning) > 0 {
+		sql += " RETURNING " + strings.Join(b.returning, ", ")
+	}
+	return sqlf.Sprintf(sql, valuesClause)
+}
+
+// upsertStyle selects the dialect-specific upsert clause syntax.
+type upsertStyle int
+
+const (
+	// standardUpsert is the Postgres/SQLite `ON CONFLICT (...) DO
+	// UPDATE SET ...` syntax.
+	standardUpsert upsertStyle = iota
+	// mysqlUpsert is MySQL's `ON DUPLICATE KEY UPDATE ...` syntax, which
+	// has no separate conflict-target clause - MySQL infers it from the
+	// table's unique/primary key.
+	mysqlUpsert
+)
+
+// upsertQuery builds the INSERT ... upsert statement using explicit
+// argument indexing (%[n]s) so that a DoUpdate assignment whose value
+// reflect.DeepEqual-matches the Values row entry for the same column
+// reuses that entry's bound argument instead of duplicating it. This
+// reuse only makes sense against a single row, so DoUpdate requires
+// exactly one call to Values. It also requires OnConflict and DoUpdate
+// to both be set (standardUpsert needs a conflict target to put in the
+// ON CONFLICT clause; mysqlUpsert ignores onConflict, but still needs
+// DoUpdate's assignments to build a non-empty SET list), and a row whose
+// length matches Columns.
+func (b *InsertBuilder) upsertQuery(style upsertStyle) *sqlf.Query {
+	if len(b.rows) != 1 {
+		panic(fmt.Sprintf("dml: DoUpdate requires exactly one row from a single Values call, got %d", len(b.rows)))
+	}
+	if style == standardUpsert && len(b.onConflict) == 0 {
+		panic("dml: upsert requires OnConflict to be set")
+	}
+	if len(b.doUpdate) == 0 {
+		panic("dml: upsert requires DoUpdate to be set")
+	}
+	row := b.rows[0]
+	if len(row) != len(b.columns) {
+		panic(fmt.Sprintf("dml: Values call has %d value(s), want %d to match Columns", len(row), len(b.columns)))
+	}
+
+	args := make([]interface{}, len(row))
+	copy(args, row)
+
+	valuePlaceholders := make([]string, len(row))
+	for i := range row {
+		valuePlaceholders[i] = fmt.Sprintf("%%[%d]s", i+1)
+	}
+
+	assignmentText := make([]string, len(b.doUpdate))
+	for i, a := range b.doUpdate {
+		if idx := indexOfColumn(b.columns, a.Column); idx >= 0 && reflect.DeepEqual(row[idx], a.Value) {
+			assignmentText[i] = fmt.Sprintf("%s = %%[%d]s", a.Column, idx+1)
+			continue
+		}
+		args = append(args, a.Value)
+		assignmentText[i] = fmt.Sprintf("%s = %%[%d]s", a.Column, len(args))
+	}
+
+	var clause string
+	if style == mysqlUpsert {
+		clause = "ON DUPLICATE KEY UPDATE " + strings.Join(assignmentText, ", ")
+	} else {
+		clause = "ON CONFLICT (" + strings.Join(b.onConflict, ", ") + ") DO UPDATE SET " + strings.Join(assignmentText, ", ")
+	}
+
+	sql := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) %s",
+		b.table,
+		strings.Join(b.columns, ", "),
+		strings.Join(valuePlaceholders, ", "),
+		clause,
+	)
+	if len(b.returning) > 0 {
+		sql += " RETURNING " + strings.Join(b.returning, ", ")
+	}
+	return sqlf.Sprintf(sql, args...)
+}
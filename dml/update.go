@@ -0,0 +1,59 @@
+package dml
+
+import (
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// UpdateBuilder builds an UPDATE statement.
+type UpdateBuilder struct {
+	table       string
+	assignments []Assignment
+	where       *sqlf.Query
+	returning   []string
+}
+
+// Update starts building an "UPDATE table" statement.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set appends a "column = value" assignment to the SET clause.
+func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	b.assignments = append(b.assignments, Assignment{Column: column, Value: value})
+	return b
+}
+
+// Where sets the WHERE clause. cond is typically built with
+// sqlf.Sprintf or sqlf.Join.
+func (b *UpdateBuilder) Where(cond *sqlf.Query) *UpdateBuilder {
+	b.where = cond
+	return b
+}
+
+// Returning sets the RETURNING clause columns.
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	b.returning = columns
+	return b
+}
+
+// Query builds the *sqlf.Query for the statement.
+func (b *UpdateBuilder) Query() *sqlf.Query {
+	setQueries := make([]*sqlf.Query, len(b.assignments))
+	for i, a := range b.assignments {
+		setQueries[i] = sqlf.Sprintf(a.Column+" = %s", a.Value)
+	}
+	setClause := sqlf.Join(setQueries, ",")
+
+	sql := "UPDATE " + b.table + " SET %s"
+	args := []interface{}{setClause}
+	if b.where != nil {
+		sql += " WHERE %s"
+		args = append(args, b.where)
+	}
+	if len(b.returning) > 0 {
+		sql += " RETURNING " + strings.Join(b.returning, ", ")
+	}
+	return sqlf.Sprintf(sql, args...)
+}
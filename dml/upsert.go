@@ -0,0 +1,20 @@
+package dml
+
+import "github.com/keegancsmith/sqlf"
+
+// Upsert renders b (which must have Columns, Values and DoUpdate all
+// set, plus OnConflict for every dialect but MySQL - see below) using
+// the upsert syntax appropriate for d: Postgres and SQLite use
+// `ON CONFLICT (...) DO UPDATE SET ...`, MySQL uses
+// `ON DUPLICATE KEY UPDATE ...` and infers its conflict target from the
+// table's unique/primary key, so OnConflict is ignored for it. This is
+// the one place the clause shape differs across dialects; everything
+// else InsertBuilder builds is portable SQL. Upsert panics if a
+// required setting is missing, or if a Values row's length doesn't
+// match Columns.
+func Upsert(b *InsertBuilder, d sqlf.Dialect) *sqlf.Query {
+	if d == sqlf.MySQL {
+		return b.upsertQuery(mysqlUpsert)
+	}
+	return b.upsertQuery(standardUpsert)
+}
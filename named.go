@@ -0,0 +1,87 @@
+package sqlf
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// namedArg marks an argument with an explicit parameter name to be used
+// by Query.Named, instead of the default sequential p1, p2, ... naming.
+type namedArg struct {
+	name string
+	v    interface{}
+}
+
+// Named marks arg with an explicit parameter name, so that Query.Named
+// uses it as the :name (or @name) placeholder instead of a generated
+// :p1, :p2, ... name. A format string can reference arg more than once
+// (eg via explicit indexing, %[1]s); every reference shares the one
+// name and the one resulting sql.NamedArg. Outside of Query.Named (plain
+// Query/Args), Named is a no-op and arg is bound normally.
+func Named(name string, arg interface{}) interface{} {
+	return namedArg{name: name, v: arg}
+}
+
+func unwrapNamed(arg interface{}) (namedArg, bool) {
+	na, ok := arg.(namedArg)
+	return na, ok
+}
+
+// namePrefix returns the placeholder prefix character driver-native
+// named parameter binding expects for binder, eg ":" for NamedBindVar
+// and "@" for MSSQLBindVar.
+func namePrefix(binder BindVar) byte {
+	if binder == MSSQLBindVar {
+		return '@'
+	}
+	return ':'
+}
+
+// Named returns a SQL string using binder's named-parameter syntax (eg
+// :p1, :p2, ... for NamedBindVar, or @p1, @p2, ... for MSSQLBindVar)
+// along with the deduplicated sql.NamedArg values to pass alongside it,
+// for use with driver-native named/prepared statement binding. Each
+// distinct argument - per its argIndices entry, so repeated references
+// like %[1]s reuse the same name - produces exactly one sql.NamedArg,
+// named p1, p2, ... in first-use order unless the argument was wrapped
+// with Named, in which case its given name is used instead.
+//
+// Named takes a binder, rather than being the zero-argument
+// "Query.Named() (sql string, args []sql.NamedArg)" you might expect:
+// the ":" vs "@" prefix genuinely differs by driver (MSSQL's @p1 vs
+// everyone else's :p1), and Query carries no dialect of its own to infer
+// it from, so the caller has to say which one they want, the same way
+// Query.Query already takes a BindVar.
+func (q *Query) Named(binder BindVar) (string, []sql.NamedArg) {
+	indices := q.argIndices
+	if indices == nil {
+		indices = make([]int, len(q.args))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+	prefix := namePrefix(binder)
+
+	names := make(map[int]string, len(q.args))
+	var namedArgs []sql.NamedArg
+
+	ph := make([]interface{}, len(indices))
+	for i, argIdx := range indices {
+		name, ok := names[argIdx]
+		if !ok {
+			arg := q.args[argIdx]
+			val := arg
+			if na, custom := unwrapNamed(arg); custom {
+				name, val = na.name, na.v
+			} else {
+				name = "p" + strconv.Itoa(len(namedArgs)+1)
+			}
+			names[argIdx] = name
+			namedArgs = append(namedArgs, sql.Named(name, val))
+		}
+		ph[i] = ignoreFormat{string(prefix) + name}
+	}
+
+	return fmt.Sprintf(q.fmt, ph...), namedArgs
+}
@@ -0,0 +1,61 @@
+package sqlf_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+func TestQueryNamed(t *testing.T) {
+	cases := map[string]struct {
+		Query    *sqlf.Query
+		Binder   sqlf.BindVar
+		Want     string
+		WantArgs []sql.NamedArg
+	}{
+		"sequential_names": {
+			sqlf.Sprintf("WHERE a = %s AND b = %s", "foo", "bar"),
+			sqlf.NamedBindVar,
+			"WHERE a = :p1 AND b = :p2",
+			[]sql.NamedArg{sql.Named("p1", "foo"), sql.Named("p2", "bar")},
+		},
+		"explicit_index_reuses_name": {
+			sqlf.Sprintf("a = %[1]s OR a = %[1]s", "x"),
+			sqlf.NamedBindVar,
+			"a = :p1 OR a = :p1",
+			[]sql.NamedArg{sql.Named("p1", "x")},
+		},
+		"user_supplied_name": {
+			sqlf.Sprintf("WHERE id = %s", sqlf.Named("id", 42)),
+			sqlf.NamedBindVar,
+			"WHERE id = :id",
+			[]sql.NamedArg{sql.Named("id", 42)},
+		},
+		"mssql_style": {
+			sqlf.Sprintf("WHERE a = %s", "foo"),
+			sqlf.MSSQLBindVar,
+			"WHERE a = @p1",
+			[]sql.NamedArg{sql.Named("p1", "foo")},
+		},
+	}
+
+	for tn, tc := range cases {
+		gotSQL, gotArgs := tc.Query.Named(tc.Binder)
+		if gotSQL != tc.Want {
+			t.Errorf("%s: expected query: %q, got: %q", tn, tc.Want, gotSQL)
+		}
+		if !reflect.DeepEqual(gotArgs, tc.WantArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", tn, tc.WantArgs, gotArgs)
+		}
+	}
+}
+
+func TestArgsUnwrapsNamed(t *testing.T) {
+	q := sqlf.Sprintf("WHERE id = %s", sqlf.Named("id", 42))
+	want := []interface{}{42}
+	if got := q.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected args: %v, got: %v", want, got)
+	}
+}
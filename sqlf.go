@@ -32,6 +32,11 @@ type Query struct {
 	// This supports explicit argument indexing like %[1]s.
 	// If nil, placeholders map 1:1 to args (legacy behavior).
 	argIndices []int
+	// reads and writes are optional table-dependency tags carried by
+	// this Query, set via Reads, Writes, or SprintfTagged, and unioned
+	// in from any embedded *Query when composed via Sprintf or Join.
+	// They have no effect on the rendered SQL.
+	reads, writes []string
 }
 
 // directive represents a parsed fmt directive in a format string.
@@ -177,10 +182,13 @@ func Sprintf(format string, args ...interface{}) *Query {
 	// Original behavior for non-indexed format strings without explicit-index queries
 	f := make([]interface{}, len(args))
 	a := make([]interface{}, 0, len(args))
+	var reads, writes []string
 	for i, arg := range args {
 		if q, ok := arg.(*Query); ok {
 			f[i] = ignoreFormat{q.fmt}
 			a = append(a, q.args...)
+			reads = unionTags(reads, q.reads)
+			writes = unionTags(writes, q.writes)
 		} else {
 			f[i] = ignoreFormat{"%s"}
 			a = append(a, arg)
@@ -192,8 +200,10 @@ func Sprintf(format string, args ...interface{}) *Query {
 	// format string. See the literal_percent_operator test.
 	format = strings.ReplaceAll(format, "%%", "%%%%")
 	return &Query{
-		fmt:  fmt.Sprintf(format, f...),
-		args: a,
+		fmt:    fmt.Sprintf(format, f...),
+		args:   a,
+		reads:  reads,
+		writes: writes,
 	}
 }
 
@@ -220,6 +230,8 @@ func sprintfExplicit(format string, args ...interface{}) *Query {
 	// Track nested queries by pointer for reuse when the same *Query is referenced multiple times
 	nestedQueries := make(map[*Query]*nestedInfo)
 
+	var reads, writes []string
+
 	lastEnd := 0
 	currentImplicitArg := 0
 
@@ -282,6 +294,8 @@ func sprintfExplicit(format string, args ...interface{}) *Query {
 				}
 
 				resultArgs = append(resultArgs, q.args...)
+				reads = unionTags(reads, q.reads)
+				writes = unionTags(writes, q.writes)
 
 				// Cache for reuse by pointer identity
 				nestedQueries[q] = &nestedInfo{
@@ -313,9 +327,29 @@ func sprintfExplicit(format string, args ...interface{}) *Query {
 		fmt:        resultFmt.String(),
 		args:       resultArgs,
 		argIndices: argIndices,
+		reads:      reads,
+		writes:     writes,
 	}
 }
 
+// New constructs a Query directly from a pre-flattened format string (as
+// returned by Inspect, or hand-rolled using %s-style placeholders) and
+// its backing args. It is the low-level counterpart to Sprintf for
+// tooling that needs to reconstruct a Query after rewriting its
+// structure, such as sqlf/checknest.
+func New(format string, args []interface{}, argIndices []int) *Query {
+	return &Query{fmt: format, args: args, argIndices: argIndices}
+}
+
+// Inspect returns the flattened format string and backing args underlying
+// q, along with its placeholder-to-argument-index mapping (nil if q uses
+// the legacy 1:1 mapping). It is exposed for tooling that needs to
+// analyze or rewrite a Query's structure, such as sqlf/checknest, rather
+// than just render it.
+func (q *Query) Inspect() (format string, args []interface{}, argIndices []int) {
+	return q.fmt, q.args, q.argIndices
+}
+
 // Query returns a string for use in database/sql/db.Query. binder is used to
 // update the format specifiers with the relevant BindVar format
 func (q *Query) Query(binder BindVar) string {
@@ -337,16 +371,47 @@ func (q *Query) Query(binder BindVar) string {
 }
 
 // Args returns the args for use in database/sql/db.Query along with
-// q.Query()
+// q.Query(). Args wrapped with Literal or Named are unwrapped to their
+// underlying value, since outside of QueryDialect/Query.Named there is
+// no dialect or naming scheme to consult about them.
 func (q *Query) Args() []interface{} {
-	return q.args
+	args := make([]interface{}, len(q.args))
+	for i, arg := range q.args {
+		if v, ok := unwrapLiteral(arg); ok {
+			args[i] = v
+			continue
+		}
+		if v, ok := unwrapNamed(arg); ok {
+			args[i] = v.v
+			continue
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+// Reads returns the table read-dependency tags carried by q, set via
+// Reads, SprintfTagged, or inherited from any embedded *Query. It
+// reports an empty slice if q has no read tags.
+func (q *Query) Reads() []string {
+	return q.reads
+}
+
+// Writes returns the table write-dependency tags carried by q, set via
+// Writes, SprintfTagged, or inherited from any embedded *Query. It
+// reports an empty slice if q has no write tags.
+func (q *Query) Writes() []string {
+	return q.writes
 }
 
 // Join concatenates the elements of queries to create a single Query. The
 // separator string sep is placed between elements in the resulting Query.
 //
 // This is commonly used to join clauses in a WHERE query. As such sep is
-// usually "AND" or "OR".
+// usually "AND" or "OR". A tag-only Query from Reads or Writes contributes
+// no text of its own - it's dropped from the joined SQL and only its tags
+// are merged in - so it can be passed alongside real clauses without
+// producing a dangling separator.
 func Join(queries []*Query, sep string) *Query {
 	f := make([]string, 0, len(queries))
 	var a []interface{}
@@ -362,7 +427,14 @@ func Join(queries []*Query, sep string) *Query {
 	}
 
 	offset := 0
+	var reads, writes []string
 	for _, q := range queries {
+		reads = unionTags(reads, q.reads)
+		writes = unionTags(writes, q.writes)
+		if isTagOnly(q) {
+			continue
+		}
+
 		f = append(f, q.fmt)
 
 		if hasExplicitIndices {
@@ -383,8 +455,10 @@ func Join(queries []*Query, sep string) *Query {
 	}
 
 	result := &Query{
-		fmt:  strings.Join(f, " "+sep+" "),
-		args: a,
+		fmt:    strings.Join(f, " "+sep+" "),
+		args:   a,
+		reads:  reads,
+		writes: writes,
 	}
 	if hasExplicitIndices {
 		result.argIndices = argIndices
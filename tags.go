@@ -0,0 +1,66 @@
+package sqlf
+
+// Reads returns a zero-SQL Query whose only effect is contributing
+// tables to the read-dependency tag set of a composed Query (via Join).
+// It's meant to be embedded alongside real query fragments so a thin
+// cache-invalidation layer built on top of sqlf can tell which tables a
+// composed statement reads, without sqlf itself owning any cache.
+//
+// Use Join, not Sprintf, to compose a tag-only Query with real clauses:
+// Join knows it inserts the separator between elements and so can leave
+// it out for an element that contributes no text. Sprintf's format
+// string is plain text the caller wrote - sqlf has no way to tell a
+// literal "AND" meant as a clause separator from one that's load-bearing
+// SQL (eg "BETWEEN x AND %s") - so splicing a tag-only Query into a
+// Sprintf format string can leave a dangling separator behind.
+func Reads(tables ...string) *Query {
+	return &Query{reads: append([]string(nil), tables...)}
+}
+
+// Writes returns a zero-SQL Query whose only effect is contributing
+// tables to the write-dependency tag set of a composed Query (via
+// Join). See Reads.
+func Writes(tables ...string) *Query {
+	return &Query{writes: append([]string(nil), tables...)}
+}
+
+// SprintfTagged is Sprintf with explicit read/write table-dependency
+// tags attached to the result, in addition to any tags inherited from
+// embedded *Query args.
+func SprintfTagged(reads, writes []string, format string, args ...interface{}) *Query {
+	q := Sprintf(format, args...)
+	q.reads = unionTags(q.reads, reads)
+	q.writes = unionTags(q.writes, writes)
+	return q
+}
+
+// isTagOnly reports whether q is a zero-SQL, tag-only Query produced by
+// Reads or Writes: it contributes nothing but tags when embedded in a
+// composed Query, so Join must not splice it into the SQL text as if it
+// were a real clause.
+func isTagOnly(q *Query) bool {
+	return q.fmt == "" && len(q.args) == 0 && (len(q.reads) > 0 || len(q.writes) > 0)
+}
+
+// unionTags returns the set union of a and b, preserving a's order and
+// appending b's new elements after it.
+func unionTags(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, s := range a {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	for _, s := range b {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
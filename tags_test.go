@@ -0,0 +1,50 @@
+package sqlf_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+func TestTags(t *testing.T) {
+	plain := sqlf.Sprintf("SELECT * FROM users WHERE id = %d", 1)
+	if got := plain.Reads(); len(got) != 0 {
+		t.Errorf("expected no read tags on an untagged query, got: %v", got)
+	}
+	if got := plain.Writes(); len(got) != 0 {
+		t.Errorf("expected no write tags on an untagged query, got: %v", got)
+	}
+
+	tagged := sqlf.SprintfTagged([]string{"users"}, nil, "SELECT * FROM users WHERE id = %d", 1)
+	if want := []string{"users"}; !reflect.DeepEqual(tagged.Reads(), want) {
+		t.Errorf("expected read tags: %v, got: %v", want, tagged.Reads())
+	}
+	if got := tagged.Writes(); len(got) != 0 {
+		t.Errorf("expected no write tags, got: %v", got)
+	}
+
+	// Sprintf inherits tags from embedded *Query args, same as real
+	// clauses; it makes no promise about the resulting SQL text around a
+	// tag-only arg, since the surrounding text is the caller's, not
+	// sqlf's (see Reads's doc comment).
+	composed := sqlf.Sprintf("%s", sqlf.Writes("orders"))
+	if want := []string{"orders"}; !reflect.DeepEqual(composed.Writes(), want) {
+		t.Errorf("expected inherited write tags: %v, got: %v", want, composed.Writes())
+	}
+
+	// Join is the supported way to compose a tag-only Query with real
+	// clauses cleanly: it inserts the separator itself, so it can leave
+	// it out for elements that contribute no text - including a
+	// tag-only entry sandwiched between two real clauses.
+	joined := sqlf.Join([]*sqlf.Query{tagged, sqlf.Reads("orders"), sqlf.Writes("orders"), sqlf.Sprintf("x = %d", 2)}, "AND")
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(joined.Reads(), want) {
+		t.Errorf("expected unioned read tags: %v, got: %v", want, joined.Reads())
+	}
+	if want := []string{"orders"}; !reflect.DeepEqual(joined.Writes(), want) {
+		t.Errorf("expected write tags: %v, got: %v", want, joined.Writes())
+	}
+	if want := "SELECT * FROM users WHERE id = $1 AND x = $2"; joined.Query(sqlf.PostgresBindVar) != want {
+		t.Errorf("expected tag-only entries to contribute no SQL text, got: %q", joined.Query(sqlf.PostgresBindVar))
+	}
+}